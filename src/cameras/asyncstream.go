@@ -0,0 +1,329 @@
+// This file implements the optional async detection pipeline. With async enabled, a background
+// goroutine pulls frames from the source camera at its native rate and submits at most
+// max_inflight of them to the vision services concurrently, publishing the most recent
+// (frameID, detections) result. Next() no longer blocks on detection: it returns the latest
+// source frame overlaid with the freshest detections, as long as they are within max_stale_ms of
+// being published, decoupling stream FPS from detector throughput.
+
+package mycamera
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.viam.com/rdk/data"
+	"go.viam.com/rdk/gostream"
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Defaults applied when the corresponding Config field is left at its zero value.
+const (
+	defaultMaxInflight = 2
+	defaultMaxStale    = 500 * time.Millisecond
+)
+
+// frameRecord is a single frame captured by the pipeline's background puller, along with its
+// sequence number, which orders the detection results published for it.
+type frameRecord struct {
+	frameID uint64
+	img     image.Image
+	at      time.Time
+}
+
+// asyncResult is the most recently published detection result from the background pipeline.
+type asyncResult struct {
+	frameID   uint64
+	at        time.Time
+	byService []serviceDetections
+}
+
+// asyncStats accumulates the rolling metrics exposed via DoCommand({"stats": true}).
+type asyncStats struct {
+	mu         sync.Mutex
+	frameTimes []time.Time
+	latencies  []time.Duration
+	dropCount  int64
+}
+
+func (s *asyncStats) recordFrame(at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.frameTimes = append(s.frameTimes, at)
+	cutoff := at.Add(-2 * time.Second)
+	i := 0
+	for i < len(s.frameTimes) && s.frameTimes[i].Before(cutoff) {
+		i++
+	}
+	s.frameTimes = s.frameTimes[i:]
+}
+
+func (s *asyncStats) recordLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latencies = append(s.latencies, d)
+	if len(s.latencies) > 200 {
+		s.latencies = s.latencies[len(s.latencies)-200:]
+	}
+}
+
+func (s *asyncStats) recordDrop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.dropCount++
+}
+
+// snapshot computes fps (over the last two seconds of captured frames), p50/p95 detector latency,
+// and the cumulative drop count.
+func (s *asyncStats) snapshot() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fps := 0.0
+	if len(s.frameTimes) >= 2 {
+		span := s.frameTimes[len(s.frameTimes)-1].Sub(s.frameTimes[0]).Seconds()
+		if span > 0 {
+			fps = float64(len(s.frameTimes)-1) / span
+		}
+	}
+
+	sorted := append([]time.Duration(nil), s.latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	return map[string]interface{}{
+		"fps":                     fps,
+		"detector_latency_p50_ms": percentile(sorted, 0.5).Seconds() * 1000,
+		"detector_latency_p95_ms": percentile(sorted, 0.95).Seconds() * 1000,
+		"drop_count":              s.dropCount,
+	}
+}
+
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	idx := int(p * float64(len(sorted)-1))
+	return sorted[idx]
+}
+
+// asyncPipeline decouples frame capture and detection from the rate at which a stream consumer
+// calls Next.
+type asyncPipeline struct {
+	of          *objectFilter
+	src         gostream.VideoStream
+	maxInflight int
+	maxStale    time.Duration
+
+	frameCounter uint64
+	latestFrame  atomic.Pointer[frameRecord]
+	latestResult atomic.Pointer[asyncResult]
+	inflight     chan struct{}
+
+	stats asyncStats
+
+	// stoppedMu guards stoppedErr/stoppedAt, set if the background puller exits early because
+	// src.Next returned a non-cancellation error, so DoCommand({"stats": true}) can surface a
+	// frozen stream instead of it looking healthy forever.
+	stoppedMu  sync.Mutex
+	stoppedErr error
+	stoppedAt  time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// recordStopped marks the background puller as having exited due to err, for later inspection via
+// stats.
+func (p *asyncPipeline) recordStopped(err error) {
+	p.stoppedMu.Lock()
+	defer p.stoppedMu.Unlock()
+	p.stoppedErr = err
+	p.stoppedAt = time.Now()
+}
+
+// stoppedState reports whether the background puller has exited early, and why.
+func (p *asyncPipeline) stoppedState() (stopped bool, err error, at time.Time) {
+	p.stoppedMu.Lock()
+	defer p.stoppedMu.Unlock()
+	return p.stoppedErr != nil, p.stoppedErr, p.stoppedAt
+}
+
+// newAsyncPipeline starts the background puller/detector goroutine over src.
+func newAsyncPipeline(of *objectFilter, src gostream.VideoStream, maxInflight int, maxStale time.Duration) *asyncPipeline {
+	if maxInflight <= 0 {
+		maxInflight = defaultMaxInflight
+	}
+	if maxStale <= 0 {
+		maxStale = defaultMaxStale
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	p := &asyncPipeline{
+		of:          of,
+		src:         src,
+		maxInflight: maxInflight,
+		maxStale:    maxStale,
+		inflight:    make(chan struct{}, maxInflight),
+		cancel:      cancel,
+		done:        make(chan struct{}),
+	}
+	go p.run(ctx)
+	return p
+}
+
+// run pulls frames at native rate and dispatches detection work, dropping frames once
+// max_inflight detections are already in flight rather than queuing them up.
+func (p *asyncPipeline) run(ctx context.Context) {
+	defer close(p.done)
+	for ctx.Err() == nil {
+		img, release, err := p.src.Next(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.of.logger.Warnf("async frame capture failed, stream is now frozen: %v", err)
+				p.recordStopped(err)
+			}
+			return
+		}
+		// Copy into a buffer the pipeline owns so the frame outlives the source's release.
+		snapshot := image.NewNRGBA(img.Bounds())
+		draw.Draw(snapshot, snapshot.Bounds(), img, img.Bounds().Min, draw.Src)
+		if release != nil {
+			release()
+		}
+
+		at := time.Now()
+		frameID := atomic.AddUint64(&p.frameCounter, 1)
+		p.stats.recordFrame(at)
+		p.latestFrame.Store(&frameRecord{frameID: frameID, img: snapshot, at: at})
+
+		select {
+		case p.inflight <- struct{}{}:
+		default:
+			p.stats.recordDrop()
+			continue
+		}
+		go func(img image.Image, frameID uint64) {
+			defer func() { <-p.inflight }()
+			start := time.Now()
+			byService, err := p.of.detectAll(ctx, img)
+			if err != nil {
+				p.of.logger.Warnf("async detection failed: %v", err)
+				return
+			}
+			p.stats.recordLatency(time.Since(start))
+			p.publishResult(&asyncResult{frameID: frameID, at: time.Now(), byService: byService})
+		}(snapshot, frameID)
+	}
+}
+
+// publishResult stores result as latestResult, unless a result for a newer frame has already been
+// published. Up to max_inflight detections can run concurrently with no ordering guarantee, so a
+// slower call for an older frameID can finish after a faster call for a newer one; without this
+// check it would overwrite latestResult with stale detections and reset the staleness clock.
+func (p *asyncPipeline) publishResult(result *asyncResult) {
+	for {
+		existing := p.latestResult.Load()
+		if existing != nil && existing.frameID >= result.frameID {
+			return
+		}
+		if p.latestResult.CompareAndSwap(existing, result) {
+			return
+		}
+	}
+}
+
+// next waits for the first captured frame (if necessary) and returns it, along with the freshest
+// published per-service detections (not yet fused or filtered), or nil if those are older than
+// max_stale.
+func (p *asyncPipeline) next(ctx context.Context) (image.Image, []serviceDetections, error) {
+	rec := p.latestFrame.Load()
+	for rec == nil {
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		case <-time.After(5 * time.Millisecond):
+			rec = p.latestFrame.Load()
+		}
+	}
+
+	res := p.latestResult.Load()
+	if res == nil || time.Since(res.at) > p.maxStale {
+		// TODO: reproject the last known boxes using a motion-compensation offset instead of
+		// falling back to an unannotated frame.
+		return rec.img, nil, nil
+	}
+	return rec.img, res.byService, nil
+}
+
+// close stops the background goroutine and releases the underlying source stream.
+func (p *asyncPipeline) close(ctx context.Context) error {
+	p.cancel()
+	<-p.done
+	return p.src.Close(ctx)
+}
+
+// asyncFilterStream is the gostream.VideoStream returned by objectFilter.Stream when async is
+// enabled.
+type asyncFilterStream struct {
+	pipeline *asyncPipeline
+	of       *objectFilter
+}
+
+// Next returns the freshest captured frame, overlaid with the freshest detections available for
+// it, without blocking on the vision service.
+func (fs asyncFilterStream) Next(ctx context.Context) (image.Image, func(), error) {
+	img, byService, err := fs.pipeline.next(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	noop := func() {}
+
+	var relevantdDetections []objectdetection.Detection
+	var fused []objectdetection.Detection
+	if byService != nil {
+		fused = fs.of.fuse(byService)
+		bounds := img.Bounds()
+		fctx := frameCtx{frameWidth: bounds.Dx(), frameHeight: bounds.Dy(), detections: fused}
+		for _, d := range fused {
+			if fs.of.filterExpr(d, fctx) {
+				relevantdDetections = append(relevantdDetections, d)
+			}
+		}
+		if fs.of.regions != nil {
+			relevantdDetections = fs.of.regions.filterDetections(relevantdDetections, image.Point{X: bounds.Dx(), Y: bounds.Dy()})
+		}
+	}
+	// Blur regions apply to every outgoing frame, including one returned without fresh detections.
+	if fs.of.regions != nil {
+		img = fs.of.regions.blur(img, fused)
+	}
+
+	// Debounce the capture decision exactly like the synchronous path, so setting async: true on a
+	// camera that relies on filter_data doesn't silently start capturing every frame.
+	captureActive, trackIDs := fs.of.captureActive(relevantdDetections, time.Now())
+	// Publish this frame's relevant detections to any live DoCommand subscriptions, same as the
+	// synchronous path.
+	frameSeq := atomic.AddUint64(&fs.of.frameSeq, 1)
+	fs.of.events.publish(frameSeq, relevantdDetections, trackIDs)
+	if (ctx.Value(data.FromDMContextKey{}) == true) && !captureActive && fs.of.conf.FilterData {
+		return nil, noop, data.ErrNoCaptureToStore
+	}
+
+	if len(relevantdDetections) > 0 && fs.of.conf.DisplayBoxes {
+		modImg, err := objectdetection.Overlay(img, relevantdDetections)
+		if err != nil {
+			return nil, noop, fmt.Errorf("could not overlay bounding boxes: %w", err)
+		}
+		return modImg, noop, nil
+	}
+	return img, noop, nil
+}
+
+// Close stops the background pipeline and closes the underlying camera stream.
+func (fs asyncFilterStream) Close(ctx context.Context) error {
+	return fs.pipeline.close(ctx)
+}
@@ -0,0 +1,94 @@
+package mycamera
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+)
+
+func TestAsyncPipelineNextReturnsNilDetectionsWhenStale(t *testing.T) {
+	p := &asyncPipeline{maxStale: 10 * time.Millisecond}
+	p.latestFrame.Store(&frameRecord{frameID: 1, img: image.NewNRGBA(image.Rect(0, 0, 1, 1)), at: time.Now()})
+	p.latestResult.Store(&asyncResult{frameID: 1, at: time.Now().Add(-time.Second)})
+
+	_, byService, err := p.next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byService != nil {
+		t.Fatalf("expected stale detections to be dropped in favor of an unannotated frame, got %v", byService)
+	}
+}
+
+func TestAsyncPipelineNextReturnsFreshDetections(t *testing.T) {
+	p := &asyncPipeline{maxStale: time.Second}
+	p.latestFrame.Store(&frameRecord{frameID: 1, img: image.NewNRGBA(image.Rect(0, 0, 1, 1)), at: time.Now()})
+	fresh := []serviceDetections{{service: "svc-a"}}
+	p.latestResult.Store(&asyncResult{frameID: 1, at: time.Now(), byService: fresh})
+
+	_, byService, err := p.next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if byService == nil {
+		t.Fatalf("expected fresh detections to be returned")
+	}
+}
+
+func TestPublishResultPreservesFrameIDMonotonicity(t *testing.T) {
+	p := &asyncPipeline{}
+	p.publishResult(&asyncResult{frameID: 5})
+	// A result for an older frame, arriving after a newer one, must not overwrite it.
+	p.publishResult(&asyncResult{frameID: 3})
+
+	if got := p.latestResult.Load().frameID; got != 5 {
+		t.Fatalf("expected latestResult to stay at the newer frameID 5, got %d", got)
+	}
+
+	p.publishResult(&asyncResult{frameID: 7})
+	if got := p.latestResult.Load().frameID; got != 7 {
+		t.Fatalf("expected latestResult to advance to the newer frameID 7, got %d", got)
+	}
+}
+
+func TestAsyncStatsSnapshotReportsDropCount(t *testing.T) {
+	s := &asyncStats{}
+	s.recordDrop()
+	s.recordDrop()
+	snap := s.snapshot()
+	if snap["drop_count"] != int64(2) {
+		t.Fatalf("expected drop_count to be 2, got %v", snap["drop_count"])
+	}
+}
+
+func TestAsyncStatsRecordFrameTrimsEntriesOlderThanTwoSeconds(t *testing.T) {
+	s := &asyncStats{}
+	now := time.Now()
+	s.recordFrame(now.Add(-5 * time.Second))
+	s.recordFrame(now)
+	s.mu.Lock()
+	n := len(s.frameTimes)
+	s.mu.Unlock()
+	if n != 1 {
+		t.Fatalf("expected the stale frame timestamp to be trimmed, got %d remaining entries", n)
+	}
+}
+
+func TestPercentileEmptyIsZero(t *testing.T) {
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Fatalf("expected percentile of an empty slice to be 0, got %v", got)
+	}
+}
+
+func TestPipelineRecordStoppedSurfacesPullerFailure(t *testing.T) {
+	p := &asyncPipeline{}
+	if stopped, _, _ := p.stoppedState(); stopped {
+		t.Fatalf("expected a fresh pipeline to not be marked stopped")
+	}
+	p.recordStopped(context.DeadlineExceeded)
+	stopped, err, at := p.stoppedState()
+	if !stopped || err != context.DeadlineExceeded || at.IsZero() {
+		t.Fatalf("expected recordStopped to mark the pipeline stopped with its error, got stopped=%v err=%v at=%v", stopped, err, at)
+	}
+}
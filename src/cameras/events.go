@@ -0,0 +1,168 @@
+// This file implements a lightweight, in-memory pub/sub layered on top of DoCommand, so automation
+// can react to filtered detections without scraping the video stream. A client subscribes with
+// DoCommand({"subscribe": "<id>", "labels": [...], "min_confidence": 0.5}) and then polls with
+// DoCommand({"poll": "<id>", "since": <seq>}) to retrieve the detection events published since the
+// given sequence number, or unsubscribes with DoCommand({"unsubscribe": "<id>"}).
+
+package mycamera
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Defaults governing a subscription's bounded ring buffer and idle cleanup.
+const (
+	subscriptionBufferSize = 256
+	subscriptionTTL        = 5 * time.Minute
+)
+
+// detectionEvent is a single published detection, relative to a subscription's filter.
+type detectionEvent struct {
+	Seq       uint64    `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	FrameSeq  uint64    `json:"frame_seq"`
+	Label     string    `json:"label"`
+	Score     float64   `json:"score"`
+	BBox      [4]int    `json:"bbox"`
+	// TrackID is the temporal tracker's id for this detection, or -1 if tracking is disabled.
+	TrackID int `json:"track_id"`
+}
+
+// subscription is a single client's filter plus a bounded ring buffer of events matching it.
+type subscription struct {
+	mu            sync.Mutex
+	labels        []string
+	minConfidence float64
+	events        []detectionEvent
+	nextSeq       uint64
+	lastPolled    time.Time
+}
+
+func newSubscription(labels []string, minConfidence float64) *subscription {
+	return &subscription{labels: labels, minConfidence: minConfidence, lastPolled: time.Now()}
+}
+
+// matches reports whether a detection with the given label and score passes this subscription's
+// filter. An empty labels list matches every label.
+func (s *subscription) matches(label string, score float64) bool {
+	if score < s.minConfidence {
+		return false
+	}
+	return len(s.labels) == 0 || slices.Contains(s.labels, label)
+}
+
+func (s *subscription) publish(ev detectionEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSeq++
+	ev.Seq = s.nextSeq
+	s.events = append(s.events, ev)
+	if len(s.events) > subscriptionBufferSize {
+		s.events = s.events[len(s.events)-subscriptionBufferSize:]
+	}
+}
+
+// poll returns every retained event with a sequence number greater than since, and marks this
+// subscription as recently active.
+func (s *subscription) poll(since uint64) []detectionEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastPolled = time.Now()
+	var out []detectionEvent
+	for _, ev := range s.events {
+		if ev.Seq > since {
+			out = append(out, ev)
+		}
+	}
+	return out
+}
+
+func (s *subscription) idleSince(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return now.Sub(s.lastPolled)
+}
+
+// eventBus fans each frame's detections out to every subscription whose filter matches.
+type eventBus struct {
+	mu   sync.Mutex
+	subs map[string]*subscription
+}
+
+func newEventBus() *eventBus {
+	return &eventBus{subs: make(map[string]*subscription)}
+}
+
+func (b *eventBus) subscribe(id string, labels []string, minConfidence float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs[id] = newSubscription(labels, minConfidence)
+}
+
+func (b *eventBus) unsubscribe(id string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subs, id)
+}
+
+func (b *eventBus) poll(id string, since uint64) ([]detectionEvent, bool) {
+	b.mu.Lock()
+	sub, ok := b.subs[id]
+	b.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+	return sub.poll(since), true
+}
+
+// publish fans a frame's fused detections out to every subscription whose filter matches. trackIDs
+// is aligned with detections and may be nil when the temporal tracker is disabled.
+func (b *eventBus) publish(frameSeq uint64, detections []objectdetection.Detection, trackIDs []int) {
+	b.mu.Lock()
+	subs := make([]*subscription, 0, len(b.subs))
+	for _, sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.Unlock()
+	if len(subs) == 0 {
+		return
+	}
+
+	now := time.Now()
+	for i, d := range detections {
+		trackID := -1
+		if trackIDs != nil {
+			trackID = trackIDs[i]
+		}
+		box := d.BoundingBox()
+		ev := detectionEvent{
+			Timestamp: now,
+			FrameSeq:  frameSeq,
+			Label:     d.Label(),
+			Score:     d.Score(),
+			BBox:      [4]int{box.Min.X, box.Min.Y, box.Max.X, box.Max.Y},
+			TrackID:   trackID,
+		}
+		for _, sub := range subs {
+			if sub.matches(d.Label(), d.Score()) {
+				sub.publish(ev)
+			}
+		}
+	}
+}
+
+// cleanupIdle removes subscriptions that haven't been polled within subscriptionTTL.
+func (b *eventBus) cleanupIdle() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	for id, sub := range b.subs {
+		if sub.idleSince(now) > subscriptionTTL {
+			delete(b.subs, id)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package mycamera
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestEventBusPublishMatchesLabelAndConfidence(t *testing.T) {
+	b := newEventBus()
+	b.subscribe("sub1", []string{"person"}, 0.5)
+
+	detections := []objectdetection.Detection{
+		objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.9, "person"),
+		objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.2, "person"),
+		objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.9, "car"),
+	}
+	b.publish(1, detections, nil)
+
+	events, ok := b.poll("sub1", 0)
+	if !ok {
+		t.Fatalf("expected subscription to exist")
+	}
+	if len(events) != 1 || events[0].Label != "person" || events[0].Score != 0.9 {
+		t.Fatalf("expected only the high-confidence person detection to match, got %+v", events)
+	}
+}
+
+func TestEventBusPollOnlyReturnsEventsSinceSeq(t *testing.T) {
+	b := newEventBus()
+	b.subscribe("sub1", nil, 0)
+	d := detectionAt("person", image.Rect(0, 0, 10, 10))
+
+	b.publish(1, []objectdetection.Detection{d}, nil)
+	first, _ := b.poll("sub1", 0)
+	if len(first) != 1 {
+		t.Fatalf("expected 1 event on first poll, got %d", len(first))
+	}
+
+	b.publish(2, []objectdetection.Detection{d}, nil)
+	second, _ := b.poll("sub1", first[len(first)-1].Seq)
+	if len(second) != 1 {
+		t.Fatalf("expected only the newly published event on a poll since the last seq, got %d", len(second))
+	}
+}
+
+func TestEventBusUnsubscribeStopsPoll(t *testing.T) {
+	b := newEventBus()
+	b.subscribe("sub1", nil, 0)
+	b.unsubscribe("sub1")
+
+	if _, ok := b.poll("sub1", 0); ok {
+		t.Fatalf("expected poll to report the subscription no longer exists after unsubscribe")
+	}
+}
+
+func TestSubscriptionRingBufferIsBounded(t *testing.T) {
+	s := newSubscription(nil, 0)
+	for i := 0; i < subscriptionBufferSize+10; i++ {
+		s.publish(detectionEvent{})
+	}
+	if len(s.events) != subscriptionBufferSize {
+		t.Fatalf("expected the ring buffer to stay bounded at %d, got %d", subscriptionBufferSize, len(s.events))
+	}
+}
+
+func TestEventBusCleanupIdleRemovesStaleSubscriptions(t *testing.T) {
+	b := newEventBus()
+	b.subscribe("sub1", nil, 0)
+	b.subs["sub1"].lastPolled = time.Now().Add(-subscriptionTTL - time.Minute)
+
+	b.cleanupIdle()
+	if _, ok := b.poll("sub1", 0); ok {
+		t.Fatalf("expected an idle subscription to be removed by cleanupIdle")
+	}
+}
+
+func TestEventBusPublishTracksTrackID(t *testing.T) {
+	b := newEventBus()
+	b.subscribe("sub1", nil, 0)
+	d := detectionAt("person", image.Rect(0, 0, 10, 10))
+
+	b.publish(1, []objectdetection.Detection{d}, []int{42})
+	events, _ := b.poll("sub1", 0)
+	if len(events) != 1 || events[0].TrackID != 42 {
+		t.Fatalf("expected the published event to carry track id 42, got %+v", events)
+	}
+}
+
+func TestEventBusPublishDefaultsTrackIDWhenTrackingDisabled(t *testing.T) {
+	b := newEventBus()
+	b.subscribe("sub1", nil, 0)
+	d := detectionAt("person", image.Rect(0, 0, 10, 10))
+
+	b.publish(1, []objectdetection.Detection{d}, nil)
+	events, _ := b.poll("sub1", 0)
+	if len(events) != 1 || events[0].TrackID != -1 {
+		t.Fatalf("expected track id -1 when tracking is disabled, got %+v", events)
+	}
+}
@@ -0,0 +1,349 @@
+// This file implements the compound label-expression filter language used to decide which
+// detections are relevant for overlay and data capture. Expressions are compiled once, at config
+// time, into a predicate over a single detection plus the surrounding frame, so that evaluating a
+// frame's detections at stream time never re-parses the expression.
+//
+// Grammar (informal):
+//
+//	expr       := orExpr
+//	orExpr     := andExpr ("OR" andExpr)*
+//	andExpr    := unary ("AND" unary)*
+//	unary      := "NOT" unary | "(" expr ")" | comparison | aggregate
+//	comparison := field op value
+//	aggregate  := "count" "(" expr ")" op number
+//	field      := "label" | "score" | "area" | "aspect" | "cx" | "cy" | "inside"
+//	op         := "=" | "==" | "!=" | ">=" | "<=" | ">" | "<"
+//
+// "inside" takes a polygon literal instead of a number: normalized (x, y) vertices, each pair
+// joined by "," and vertices joined by ";", e.g. "inside=0.1,0.1;0.9,0.1;0.9,0.9;0.1,0.9". A
+// detection is inside when its bounding-box center falls within the polygon.
+package mycamera
+
+import (
+	"fmt"
+	"image"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// polygonScale converts the normalized [0, 1] coordinates used by "inside" polygon literals (and
+// by cx/cy) into the integer pixel space pointInPolygon operates on, without losing precision.
+const polygonScale = 1 << 20
+
+// frameCtx carries per-frame information a compiled filterExpr needs beyond a single detection:
+// the frame dimensions (for normalized geometry predicates) and the full set of detections in the
+// frame (for count() aggregates).
+type frameCtx struct {
+	frameWidth  int
+	frameHeight int
+	detections  []objectdetection.Detection
+}
+
+// filterExpr is a compiled predicate deciding whether a single detection is relevant, given the
+// frame it was found in.
+type filterExpr func(d objectdetection.Detection, fctx frameCtx) bool
+
+// compileFilter parses and compiles a filter expression string into a filterExpr. An empty
+// expression compiles to a predicate that always returns false, matching the historical behavior
+// of an empty Labels list.
+func compileFilter(expr string) (filterExpr, error) {
+	if strings.TrimSpace(expr) == "" {
+		return func(objectdetection.Detection, frameCtx) bool { return false }, nil
+	}
+	p := &exprParser{tokens: tokenizeFilter(expr)}
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("could not compile filter %q: %w", expr, err)
+	}
+	if !p.atEnd() {
+		return nil, fmt.Errorf("could not compile filter %q: unexpected token %q", expr, p.peek())
+	}
+	return node, nil
+}
+
+// labelsConfidenceFilter lowers the legacy Labels/Confidence fields into the same filterExpr form
+// used by the compiled filter language, so both configuration styles share one evaluation path.
+func labelsConfidenceFilter(labels []string, confidence float64) filterExpr {
+	return func(d objectdetection.Detection, _ frameCtx) bool {
+		return slices.Contains(labels, d.Label()) && d.Score() >= confidence
+	}
+}
+
+// tokenizeFilter splits a filter expression into tokens. Multi-character operators (==, !=, >=,
+// <=) are recognized greedily; bare identifiers/numbers are split on whitespace, parentheses and
+// operator characters.
+func tokenizeFilter(expr string) []string {
+	var tokens []string
+	var cur strings.Builder
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	runes := []rune(expr)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			flush()
+		case r == '(' || r == ')':
+			flush()
+			tokens = append(tokens, string(r))
+		case strings.ContainsRune("=!><", r):
+			flush()
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, string(r)+"=")
+				i++
+			} else {
+				tokens = append(tokens, string(r))
+			}
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// exprParser is a small recursive-descent parser over a flat token stream.
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) atEnd() bool { return p.pos >= len(p.tokens) }
+
+func (p *exprParser) peek() string {
+	if p.atEnd() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *exprParser) peekUpper() string { return strings.ToUpper(p.peek()) }
+
+func (p *exprParser) parseOr() (filterExpr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekUpper() == "OR" {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(d objectdetection.Detection, fctx frameCtx) bool { return l(d, fctx) || r(d, fctx) }
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAnd() (filterExpr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peekUpper() == "AND" {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l, r := left, right
+		left = func(d objectdetection.Detection, fctx frameCtx) bool { return l(d, fctx) && r(d, fctx) }
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseUnary() (filterExpr, error) {
+	if p.peekUpper() == "NOT" {
+		p.next()
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(d objectdetection.Detection, fctx frameCtx) bool { return !inner(d, fctx) }, nil
+	}
+	if p.peek() == "(" {
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("expected closing parenthesis")
+		}
+		return inner, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (filterExpr, error) {
+	field := p.next()
+	if field == "" {
+		return nil, fmt.Errorf("expected field or function")
+	}
+
+	if strings.EqualFold(field, "count") {
+		if p.next() != "(" {
+			return nil, fmt.Errorf("expected '(' after count")
+		}
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("expected ')' closing count(...)")
+		}
+		op := p.next()
+		threshold, err := strconv.Atoi(p.next())
+		if err != nil {
+			return nil, fmt.Errorf("count(...) requires a numeric threshold: %w", err)
+		}
+		return func(_ objectdetection.Detection, fctx frameCtx) bool {
+			n := 0
+			for _, other := range fctx.detections {
+				if inner(other, fctx) {
+					n++
+				}
+			}
+			return compareNumbers(float64(n), op, float64(threshold))
+		}, nil
+	}
+
+	op := p.next()
+	value := p.next()
+	if op == "" || value == "" {
+		return nil, fmt.Errorf("expected operator and value after field %q", field)
+	}
+
+	switch strings.ToLower(field) {
+	case "label":
+		return func(d objectdetection.Detection, _ frameCtx) bool {
+			return compareStrings(d.Label(), op, value)
+		}, nil
+	case "inside":
+		polygon, err := parsePolygonLiteral(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %w", field, err)
+		}
+		return func(d objectdetection.Detection, fctx frameCtx) bool {
+			pt := image.Point{
+				X: int(geometryField("cx", d, fctx) * polygonScale),
+				Y: int(geometryField("cy", d, fctx) * polygonScale),
+			}
+			in := pointInPolygon(pt, polygon)
+			return ((op == "=" || op == "==") && in) || (op == "!=" && !in)
+		}, nil
+	case "score", "area", "aspect", "cx", "cy":
+		threshold, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %q requires a numeric value: %w", field, err)
+		}
+		return func(d objectdetection.Detection, fctx frameCtx) bool {
+			return compareNumbers(geometryField(field, d, fctx), op, threshold)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown filter field: %s", field)
+	}
+}
+
+// geometryField evaluates a numeric field (score or a bounding-box geometry predicate) for d.
+func geometryField(field string, d objectdetection.Detection, fctx frameCtx) float64 {
+	if field == "score" {
+		return d.Score()
+	}
+	box := d.BoundingBox()
+	w, h := float64(box.Dx()), float64(box.Dy())
+	switch field {
+	case "area":
+		if fctx.frameWidth == 0 || fctx.frameHeight == 0 {
+			return 0
+		}
+		return (w * h) / float64(fctx.frameWidth*fctx.frameHeight)
+	case "aspect":
+		if h == 0 {
+			return 0
+		}
+		return w / h
+	case "cx":
+		if fctx.frameWidth == 0 {
+			return 0
+		}
+		return (float64(box.Min.X) + w/2) / float64(fctx.frameWidth)
+	case "cy":
+		if fctx.frameHeight == 0 {
+			return 0
+		}
+		return (float64(box.Min.Y) + h/2) / float64(fctx.frameHeight)
+	default:
+		return 0
+	}
+}
+
+// parsePolygonLiteral parses an "inside" field's polygon literal ("x1,y1;x2,y2;...", normalized
+// [0, 1] coordinates) into pixel-space vertices in polygonScale units, matching the scale used for
+// a detection's cx/cy when testing membership.
+func parsePolygonLiteral(literal string) ([]image.Point, error) {
+	var polygon []image.Point
+	for _, vertex := range strings.Split(literal, ";") {
+		coords := strings.Split(vertex, ",")
+		if len(coords) != 2 {
+			return nil, fmt.Errorf("invalid polygon vertex %q, expected \"x,y\"", vertex)
+		}
+		x, err := strconv.ParseFloat(coords[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polygon vertex %q: %w", vertex, err)
+		}
+		y, err := strconv.ParseFloat(coords[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid polygon vertex %q: %w", vertex, err)
+		}
+		polygon = append(polygon, image.Point{X: int(x * polygonScale), Y: int(y * polygonScale)})
+	}
+	if len(polygon) < 3 {
+		return nil, fmt.Errorf("polygon needs at least 3 vertices")
+	}
+	return polygon, nil
+}
+
+func compareNumbers(actual float64, op string, threshold float64) bool {
+	switch op {
+	case "=", "==":
+		return actual == threshold
+	case "!=":
+		return actual != threshold
+	case ">=":
+		return actual >= threshold
+	case "<=":
+		return actual <= threshold
+	case ">":
+		return actual > threshold
+	case "<":
+		return actual < threshold
+	default:
+		return false
+	}
+}
+
+func compareStrings(actual string, op string, value string) bool {
+	switch op {
+	case "=", "==":
+		return actual == value
+	case "!=":
+		return actual != value
+	default:
+		return false
+	}
+}
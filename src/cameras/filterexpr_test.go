@@ -0,0 +1,125 @@
+package mycamera
+
+import (
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func mustCompile(t *testing.T, expr string) filterExpr {
+	t.Helper()
+	f, err := compileFilter(expr)
+	if err != nil {
+		t.Fatalf("compileFilter(%q): %v", expr, err)
+	}
+	return f
+}
+
+func TestCompileFilterEmptyExpressionAlwaysFalse(t *testing.T) {
+	f := mustCompile(t, "")
+	d := detectionAt("person", image.Rect(0, 0, 10, 10))
+	if f(d, frameCtx{}) {
+		t.Fatalf("expected empty filter expression to never match")
+	}
+}
+
+func TestCompileFilterLabelEquality(t *testing.T) {
+	f := mustCompile(t, `label=person`)
+	fctx := frameCtx{}
+	if !f(detectionAt("person", image.Rect(0, 0, 10, 10)), fctx) {
+		t.Fatalf("expected label=person to match a person detection")
+	}
+	if f(detectionAt("car", image.Rect(0, 0, 10, 10)), fctx) {
+		t.Fatalf("expected label=person to not match a car detection")
+	}
+}
+
+func TestCompileFilterScoreComparison(t *testing.T) {
+	f := mustCompile(t, `score>=0.8`)
+	fctx := frameCtx{}
+	high := objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.9, "person")
+	low := objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.5, "person")
+	if !f(high, fctx) {
+		t.Fatalf("expected score>=0.8 to match a 0.9 score detection")
+	}
+	if f(low, fctx) {
+		t.Fatalf("expected score>=0.8 to not match a 0.5 score detection")
+	}
+}
+
+func TestCompileFilterAndOrPrecedence(t *testing.T) {
+	// AND binds tighter than OR: label=cat OR label=dog AND score>=0.9 means
+	// "cat, regardless of score" OR "dog with score>=0.9".
+	f := mustCompile(t, `label=cat OR label=dog AND score>=0.9`)
+	fctx := frameCtx{}
+
+	lowScoreCat := objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.1, "cat")
+	if !f(lowScoreCat, fctx) {
+		t.Fatalf("expected a low-score cat to match via the OR branch")
+	}
+
+	lowScoreDog := objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.1, "dog")
+	if f(lowScoreDog, fctx) {
+		t.Fatalf("expected a low-score dog to not match, since AND binds tighter than OR")
+	}
+
+	highScoreDog := objectdetection.NewDetection(boxPtr(image.Rect(0, 0, 10, 10)), 0.95, "dog")
+	if !f(highScoreDog, fctx) {
+		t.Fatalf("expected a high-score dog to match via the AND branch")
+	}
+}
+
+func TestCompileFilterNotAndParens(t *testing.T) {
+	f := mustCompile(t, `NOT (label=cat OR label=dog)`)
+	fctx := frameCtx{}
+	if f(detectionAt("cat", image.Rect(0, 0, 10, 10)), fctx) {
+		t.Fatalf("expected NOT (cat OR dog) to exclude a cat")
+	}
+	if !f(detectionAt("bird", image.Rect(0, 0, 10, 10)), fctx) {
+		t.Fatalf("expected NOT (cat OR dog) to include a bird")
+	}
+}
+
+func TestCompileFilterCountAggregate(t *testing.T) {
+	f := mustCompile(t, `count(label=person)>=2`)
+	people := []objectdetection.Detection{
+		detectionAt("person", image.Rect(0, 0, 10, 10)),
+		detectionAt("person", image.Rect(20, 20, 30, 30)),
+	}
+	fctx := frameCtx{detections: people}
+	if !f(people[0], fctx) {
+		t.Fatalf("expected count(label=person)>=2 to match when two people are present")
+	}
+
+	fctxOne := frameCtx{detections: people[:1]}
+	if f(people[0], fctxOne) {
+		t.Fatalf("expected count(label=person)>=2 to not match when only one person is present")
+	}
+}
+
+func TestCompileFilterInsidePolygon(t *testing.T) {
+	f := mustCompile(t, `inside=0,0;1,0;1,1;0,1`)
+	fctx := frameCtx{frameWidth: 100, frameHeight: 100}
+	center := detectionAt("person", image.Rect(40, 40, 60, 60))
+	if !f(center, fctx) {
+		t.Fatalf("expected a centered detection to be inside the full-frame polygon")
+	}
+}
+
+func TestCompileFilterInsideRejectsMalformedPolygon(t *testing.T) {
+	if _, err := compileFilter(`inside=0,0;1,1`); err == nil {
+		t.Fatalf("expected a two-vertex polygon literal to be rejected")
+	}
+	if _, err := compileFilter(`inside=not-a-number,0;1,1;1,0`); err == nil {
+		t.Fatalf("expected a non-numeric polygon vertex to be rejected")
+	}
+}
+
+func TestCompileFilterUnknownFieldRejected(t *testing.T) {
+	if _, err := compileFilter(`bogus=1`); err == nil {
+		t.Fatalf("expected an unknown field to be rejected")
+	}
+}
+
+func boxPtr(r image.Rectangle) *image.Rectangle { return &r }
@@ -0,0 +1,214 @@
+// This file implements the multi vision service detection fusion policies used by objectFilter
+// when more than one vision service is configured. Detections from every configured service are
+// collected per frame and combined into a single list according to the active fusion policy.
+
+package mycamera
+
+import (
+	"fmt"
+	"image"
+	"slices"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Supported fusion policy names.
+const (
+	FusionUnion        = "union"
+	FusionIntersection = "intersection"
+	FusionWeighted     = "weighted"
+)
+
+// Default IoU threshold used to decide whether two detections from different services refer to
+// the same physical object when no fusion_iou_threshold is configured.
+const defaultFusionIoUThreshold = 0.5
+
+// validateFusionMode returns an error if mode is not a recognized fusion policy. An empty string
+// is accepted and treated as the default (union) elsewhere.
+func validateFusionMode(mode string) error {
+	switch mode {
+	case "", FusionUnion, FusionIntersection, FusionWeighted:
+		return nil
+	default:
+		return fmt.Errorf("unknown fusion policy: %s", mode)
+	}
+}
+
+// serviceDetections pairs the detections returned by a single vision service with the name of
+// that service, so fusion policies can apply per-service overrides.
+type serviceDetections struct {
+	service    string
+	detections []objectdetection.Detection
+}
+
+// fuse combines detections gathered from all configured vision services into a single list
+// according to the object filter's active fusion policy, after applying each service's
+// label/confidence overrides.
+func (of *objectFilter) fuse(byService []serviceDetections) []objectdetection.Detection {
+	filtered := make([]serviceDetections, len(byService))
+	for i, sd := range byService {
+		filtered[i] = serviceDetections{service: sd.service, detections: of.filterForService(sd.service, sd.detections)}
+	}
+
+	switch of.currentFusion() {
+	case FusionIntersection:
+		return of.fuseIntersection(filtered)
+	case FusionWeighted:
+		return of.fuseWeighted(filtered)
+	default:
+		return of.fuseUnion(filtered)
+	}
+}
+
+// filterForService applies a per-service override, when one is explicitly configured for service.
+// With no override, it falls back to the legacy top-level Labels/Confidence only when those are
+// the active selection mechanism (Config.Filter unset); once Config.Filter is set, it is the
+// authoritative selection and an unconfigured service's detections pass through unfiltered into
+// fusion, to be selected by the compiled filter expression afterwards instead.
+func (of *objectFilter) filterForService(service string, detections []objectdetection.Detection) []objectdetection.Detection {
+	cfg, hasOverride := of.serviceConfigs[service]
+	if !hasOverride && of.conf.Filter != "" {
+		return detections
+	}
+
+	labels := of.conf.Labels
+	confidence := of.conf.Confidence
+	if hasOverride {
+		if len(cfg.Labels) > 0 {
+			labels = cfg.Labels
+		}
+		if cfg.MinConfidence > 0 {
+			confidence = cfg.MinConfidence
+		}
+	}
+
+	var out []objectdetection.Detection
+	for _, d := range detections {
+		if slices.Contains(labels, d.Label()) && d.Score() >= confidence {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// fuseUnion concatenates the filtered detections of every service.
+func (of *objectFilter) fuseUnion(byService []serviceDetections) []objectdetection.Detection {
+	var out []objectdetection.Detection
+	for _, sd := range byService {
+		out = append(out, sd.detections...)
+	}
+	return out
+}
+
+// fuseIntersection merges detections whose label and bounding box are matched, via IoU, by at
+// least fusionMinServices distinct services into a single output detection per matched group, so
+// the same real-world object isn't counted once per contributing service.
+func (of *objectFilter) fuseIntersection(byService []serviceDetections) []objectdetection.Detection {
+	iouThreshold := of.fusionIoUThreshold
+
+	// seen is keyed by (service, detection) so a detection already folded into one group is never
+	// reconsidered as the start of another.
+	type seenKey struct {
+		service string
+		key     int
+	}
+	seen := make(map[seenKey]bool)
+
+	var out []objectdetection.Detection
+	for i, sd := range byService {
+		for _, d := range sd.detections {
+			sk := seenKey{sd.service, detectionKey(d)}
+			if seen[sk] {
+				continue
+			}
+
+			group := []objectdetection.Detection{d}
+			groupKeys := []seenKey{sk}
+			for j, other := range byService {
+				if j == i {
+					continue
+				}
+				if match := bestMatch(d, other.detections, iouThreshold); match != nil {
+					group = append(group, match)
+					groupKeys = append(groupKeys, seenKey{other.service, detectionKey(match)})
+				}
+			}
+
+			if len(group) >= of.fusionMinServices {
+				out = append(out, highestScoring(group))
+				for _, gk := range groupKeys {
+					seen[gk] = true
+				}
+			}
+		}
+	}
+	return out
+}
+
+// fuseWeighted rescales each detection's score by its service's configured weight and keeps those
+// at or above fusionThreshold.
+func (of *objectFilter) fuseWeighted(byService []serviceDetections) []objectdetection.Detection {
+	var out []objectdetection.Detection
+	for _, sd := range byService {
+		weight := 1.0
+		if cfg, ok := of.serviceConfigs[sd.service]; ok && cfg.Weight > 0 {
+			weight = cfg.Weight
+		}
+		for _, d := range sd.detections {
+			weighted := d.Score() * weight
+			if weighted >= of.fusionThreshold {
+				out = append(out, objectdetection.NewDetection(d.BoundingBox(), weighted, d.Label()))
+			}
+		}
+	}
+	return out
+}
+
+// bestMatch returns the detection in candidates with the same label as d and the highest IoU with
+// d, provided it is at or above iouThreshold, or nil if none qualifies.
+func bestMatch(d objectdetection.Detection, candidates []objectdetection.Detection, iouThreshold float64) objectdetection.Detection {
+	var best objectdetection.Detection
+	bestIoU := iouThreshold
+	for _, c := range candidates {
+		if c.Label() != d.Label() {
+			continue
+		}
+		if v := iou(d.BoundingBox(), c.BoundingBox()); v >= bestIoU {
+			bestIoU, best = v, c
+		}
+	}
+	return best
+}
+
+// highestScoring returns the detection in group with the highest score, representing the whole
+// matched group in fuseIntersection's output.
+func highestScoring(group []objectdetection.Detection) objectdetection.Detection {
+	best := group[0]
+	for _, d := range group[1:] {
+		if d.Score() > best.Score() {
+			best = d
+		}
+	}
+	return best
+}
+
+// detectionKey produces a cheap, non-cryptographic identity for deduplicating a detection within
+// a single frame's fusion pass.
+func detectionKey(d objectdetection.Detection) int {
+	b := d.BoundingBox()
+	return b.Min.X ^ b.Min.Y<<8 ^ b.Max.X<<16 ^ b.Max.Y<<24
+}
+
+// iou returns the intersection-over-union of two rectangles, in [0, 1].
+func iou(a, b *image.Rectangle) float64 {
+	inter := a.Intersect(*b)
+	if inter.Empty() {
+		return 0
+	}
+	interArea := float64(inter.Dx() * inter.Dy())
+	unionArea := float64(a.Dx()*a.Dy()+b.Dx()*b.Dy()) - interArea
+	if unionArea <= 0 {
+		return 0
+	}
+	return interArea / unionArea
+}
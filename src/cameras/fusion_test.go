@@ -0,0 +1,103 @@
+package mycamera
+
+import (
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestFuseUnionConcatenatesAllServices(t *testing.T) {
+	of := &objectFilter{}
+	byService := []serviceDetections{
+		{service: "svc-a", detections: []objectdetection.Detection{detectionAt("person", image.Rect(0, 0, 10, 10))}},
+		{service: "svc-b", detections: []objectdetection.Detection{detectionAt("car", image.Rect(20, 20, 30, 30))}},
+	}
+	out := of.fuseUnion(byService)
+	if len(out) != 2 {
+		t.Fatalf("expected union fusion to concatenate both services' detections, got %d", len(out))
+	}
+}
+
+func TestFuseIntersectionMergesMatchedGroupIntoOneDetection(t *testing.T) {
+	of := &objectFilter{fusionMinServices: 2, fusionIoUThreshold: 0.5}
+	box := image.Rect(0, 0, 10, 10)
+	byService := []serviceDetections{
+		{service: "svc-a", detections: []objectdetection.Detection{objectdetection.NewDetection(&box, 0.7, "person")}},
+		{service: "svc-b", detections: []objectdetection.Detection{objectdetection.NewDetection(&box, 0.9, "person")}},
+	}
+	out := of.fuseIntersection(byService)
+	if len(out) != 1 {
+		t.Fatalf("expected two services agreeing on the same object to fuse into exactly one detection, got %d", len(out))
+	}
+	if out[0].Score() != 0.9 {
+		t.Fatalf("expected the merged detection to be the highest-scoring member of the group, got score %v", out[0].Score())
+	}
+}
+
+func TestFuseIntersectionDropsBelowMinServices(t *testing.T) {
+	of := &objectFilter{fusionMinServices: 2, fusionIoUThreshold: 0.5}
+	byService := []serviceDetections{
+		{service: "svc-a", detections: []objectdetection.Detection{detectionAt("person", image.Rect(0, 0, 10, 10))}},
+		{service: "svc-b", detections: []objectdetection.Detection{detectionAt("person", image.Rect(90, 90, 100, 100))}},
+	}
+	out := of.fuseIntersection(byService)
+	if len(out) != 0 {
+		t.Fatalf("expected detections seen by only one service to be dropped when fusion_min_services=2, got %d", len(out))
+	}
+}
+
+func TestFuseIntersectionHandlesThreeServicesWithoutDuplicates(t *testing.T) {
+	of := &objectFilter{fusionMinServices: 2, fusionIoUThreshold: 0.5}
+	box := image.Rect(0, 0, 10, 10)
+	byService := []serviceDetections{
+		{service: "svc-a", detections: []objectdetection.Detection{objectdetection.NewDetection(&box, 0.6, "person")}},
+		{service: "svc-b", detections: []objectdetection.Detection{objectdetection.NewDetection(&box, 0.7, "person")}},
+		{service: "svc-c", detections: []objectdetection.Detection{objectdetection.NewDetection(&box, 0.8, "person")}},
+	}
+	out := of.fuseIntersection(byService)
+	if len(out) != 1 {
+		t.Fatalf("expected three services agreeing on the same object to still fuse into exactly one detection, got %d", len(out))
+	}
+}
+
+func TestFuseWeightedAppliesPerServiceWeight(t *testing.T) {
+	of := &objectFilter{
+		fusionThreshold: 0.5,
+		serviceConfigs: map[string]VisionServiceConfig{
+			"svc-a": {Weight: 0.5},
+		},
+	}
+	box := image.Rect(0, 0, 10, 10)
+	byService := []serviceDetections{
+		{service: "svc-a", detections: []objectdetection.Detection{objectdetection.NewDetection(&box, 0.9, "person")}},
+	}
+	out := of.fuseWeighted(byService)
+	if len(out) != 0 {
+		t.Fatalf("expected a 0.9 score scaled by weight 0.5 (0.45) to fall below threshold 0.5, got %d results", len(out))
+	}
+}
+
+func TestFilterForServiceFallsBackToLegacyFieldsWithoutFilter(t *testing.T) {
+	of := &objectFilter{conf: &Config{Labels: []string{"person"}, Confidence: 0.5}, serviceConfigs: map[string]VisionServiceConfig{}}
+	detections := []objectdetection.Detection{
+		detectionAt("person", image.Rect(0, 0, 10, 10)),
+		detectionAt("car", image.Rect(0, 0, 10, 10)),
+	}
+	out := of.filterForService("svc-a", detections)
+	if len(out) != 1 || out[0].Label() != "person" {
+		t.Fatalf("expected legacy Labels/Confidence to select only the person detection, got %v", out)
+	}
+}
+
+func TestFilterForServicePassesThroughUnfilteredWhenFilterConfigured(t *testing.T) {
+	of := &objectFilter{conf: &Config{Filter: "label=person", Labels: nil}, serviceConfigs: map[string]VisionServiceConfig{}}
+	detections := []objectdetection.Detection{
+		detectionAt("person", image.Rect(0, 0, 10, 10)),
+		detectionAt("car", image.Rect(0, 0, 10, 10)),
+	}
+	out := of.filterForService("svc-a", detections)
+	if len(out) != 2 {
+		t.Fatalf("expected an unconfigured service's detections to pass through unfiltered once Config.Filter is set, got %d", len(out))
+	}
+}
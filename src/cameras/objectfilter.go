@@ -9,6 +9,9 @@ import (
 	"fmt"
 	"image"
 	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.viam.com/rdk/components/camera"
 	"go.viam.com/rdk/data"
@@ -20,6 +23,7 @@ import (
 	"go.viam.com/rdk/services/vision"
 	"go.viam.com/rdk/vision/objectdetection"
 	"go.viam.com/utils"
+	"golang.org/x/sync/errgroup"
 )
 
 func init() {
@@ -42,6 +46,64 @@ type Config struct {
 	DisplayBoxes bool `json:"display_boxes"`
 	// Activate/deactivate data recording filtering
 	FilterData bool `json:"filter_data"`
+	// Optional: Per vision service label/confidence/weight overrides, keyed by vision service name.
+	VisionServiceConfigs []VisionServiceConfig `json:"vision_service_configs"`
+	// Optional: How detections from multiple vision services are combined: "union", "intersection",
+	// or "weighted". Defaults to "union".
+	Fusion string `json:"fusion"`
+	// Optional: Minimum number of services that must agree, via IoU match, for "intersection" fusion.
+	// Defaults to the number of configured vision services.
+	FusionMinServices int `json:"fusion_min_services"`
+	// Optional: IoU threshold used to decide whether two detections from different services refer
+	// to the same object. Defaults to 0.5.
+	FusionIoUThreshold float64 `json:"fusion_iou_threshold"`
+	// Optional: Score threshold applied after weighting under the "weighted" fusion policy.
+	FusionThreshold float64 `json:"fusion_threshold"`
+	// Optional: A compound label-expression filter, e.g. "label=person AND score>=0.6 AND NOT
+	// (label=dog OR label=cat)". Applied to the fused detections of a frame. When empty, Labels and
+	// Confidence are used instead.
+	Filter string `json:"filter"`
+	// Optional: Enables the temporal tracker, which debounces FilterData decisions across frames
+	// instead of reacting to a single frame's detections. Default: false.
+	Tracking bool `json:"tracking"`
+	// Optional: Number of the last hits_window frames a track must be present in to be confirmed.
+	// Defaults to 3.
+	MinHits int `json:"min_hits"`
+	// Optional: Size of the sliding window min_hits is evaluated over. Defaults to 5.
+	HitsWindow int `json:"hits_window"`
+	// Optional: Number of consecutive missed frames before a track is dropped. Defaults to 10.
+	MaxAge int `json:"max_age"`
+	// Optional: Seconds to keep capturing after a confirmed track's last sighting. Defaults to 2.
+	Cooldown float64 `json:"cooldown"`
+	// Optional: IoU threshold used to associate a detection with an existing track. Defaults to 0.3.
+	IoUMatch float64 `json:"iou_match"`
+	// Optional: Named polygon regions, in normalized image coordinates, used to include/exclude
+	// detections or to blur the outgoing frame for privacy.
+	Regions []Region `json:"regions"`
+	// Optional: Detection labels whose bounding box is always blurred in the outgoing frame, e.g.
+	// "face" or "license_plate".
+	BlurLabels []string `json:"blur_labels"`
+	// Optional: Decouples the stream's frame rate from the vision services' throughput by running
+	// detection in a background pipeline instead of blocking Next() on it. Default: false.
+	Async bool `json:"async"`
+	// Optional: Maximum number of frames submitted to the vision services concurrently by the async
+	// pipeline. Extra frames are dropped rather than queued. Defaults to 2.
+	MaxInflight int `json:"max_inflight"`
+	// Optional: How stale, in milliseconds, a published async detection result may be before Next()
+	// falls back to returning the raw frame. Defaults to 500.
+	MaxStaleMs int `json:"max_stale_ms"`
+}
+
+// VisionServiceConfig holds per vision service overrides applied by the fusion pipeline.
+type VisionServiceConfig struct {
+	// Name of the vision service this override applies to, must match an entry in vision_services.
+	Name string `json:"name"`
+	// Optional: Labels to extract for this service. Falls back to the top-level Labels when empty.
+	Labels []string `json:"labels"`
+	// Optional: Confidence threshold for this service. Falls back to the top-level Confidence when zero.
+	MinConfidence float64 `json:"min_confidence"`
+	// Optional: Weight applied to this service's scores under the "weighted" fusion policy. Defaults to 1.
+	Weight float64 `json:"weight"`
 }
 
 // Configuration information validation, returning implicit dependencies.
@@ -52,6 +114,28 @@ func (cfg *Config) Validate(path string) ([]string, error) {
 	if len(cfg.VisionServices) == 0 {
 		return nil, utils.NewConfigValidationFieldRequiredError(path, "vision_services")
 	}
+	if err := validateFusionMode(cfg.Fusion); err != nil {
+		return nil, utils.NewConfigValidationError(path, err)
+	}
+	if cfg.Filter != "" {
+		if _, err := compileFilter(cfg.Filter); err != nil {
+			return nil, utils.NewConfigValidationError(path, err)
+		}
+	}
+	if cfg.Tracking && cfg.HitsWindow != 0 && cfg.MinHits > cfg.HitsWindow {
+		return nil, utils.NewConfigValidationError(path, fmt.Errorf("min_hits cannot exceed hits_window"))
+	}
+	for _, region := range cfg.Regions {
+		if err := region.validate(); err != nil {
+			return nil, utils.NewConfigValidationError(path, err)
+		}
+	}
+	for _, svcConf := range cfg.VisionServiceConfigs {
+		if !slices.Contains(cfg.VisionServices, svcConf.Name) {
+			return nil, utils.NewConfigValidationError(path, fmt.Errorf("vision_service_configs references unknown vision service: %s", svcConf.Name))
+		}
+	}
+
 	impDeps := cfg.VisionServices
 	impDeps = append(impDeps, cfg.Camera)
 	return impDeps, nil
@@ -68,8 +152,79 @@ type objectFilter struct {
 	logger logging.Logger
 
 	camera         camera.Camera
-	visionService  vision.Service
 	visionServices map[string]vision.Service
+
+	// activeServiceMu guards activeService, which DoCommand can flip live.
+	activeServiceMu sync.RWMutex
+	// activeService restricts detectAll to a single configured vision service when non-empty. Empty
+	// means query every service in visionServices, the default.
+	activeService string
+
+	// serviceConfigs holds per vision service label/confidence/weight overrides, keyed by service name.
+	serviceConfigs map[string]VisionServiceConfig
+	// fusionMinServices and fusionIoUThreshold/fusionThreshold parameterize the fusion policies and
+	// are fixed at config time.
+	fusionMinServices  int
+	fusionIoUThreshold float64
+	fusionThreshold    float64
+
+	// fusionMu guards fusion, which DoCommand can flip live.
+	fusionMu sync.RWMutex
+	fusion   string
+
+	// filterExpr is the compiled, final selection predicate applied to the fused detections of a
+	// frame. It is either the compiled Config.Filter, or the legacy Labels/Confidence lowered into
+	// the same form.
+	filterExpr filterExpr
+
+	// tracker debounces FilterData decisions across frames when Config.Tracking is enabled. Nil
+	// when tracking is disabled, in which case FilterData falls back to a single-frame check.
+	tracker *tracker
+
+	// regions applies include/exclude detection filtering and privacy blurring. Nil when no regions
+	// or blur_labels are configured.
+	regions *regionSet
+
+	// async/maxInflight/maxStale configure the background detection pipeline used when Config.Async
+	// is set. pipelineMu guards pipeline, which is (re)created by Stream and read by DoCommand's
+	// "stats" command.
+	async       bool
+	maxInflight int
+	maxStale    time.Duration
+
+	pipelineMu sync.Mutex
+	pipeline   *asyncPipeline
+
+	// frameSeq is a monotonically increasing per-frame counter used to tag published events.
+	frameSeq uint64
+	// events fans each frame's detections out to DoCommand subscribers.
+	events *eventBus
+}
+
+// currentFusion returns the active fusion policy, safe for concurrent use.
+func (of *objectFilter) currentFusion() string {
+	of.fusionMu.RLock()
+	defer of.fusionMu.RUnlock()
+	return of.fusion
+}
+
+// currentActiveService returns the vision service detectAll is temporarily restricted to, or "" if
+// it should query every configured service.
+func (of *objectFilter) currentActiveService() string {
+	of.activeServiceMu.RLock()
+	defer of.activeServiceMu.RUnlock()
+	return of.activeService
+}
+
+// captureActive decides whether data capture should stay active this frame, debounced across
+// frames by the temporal tracker when Config.Tracking is enabled. With tracking disabled it
+// reduces to a single-frame check, and trackIDs is nil. Shared by both the synchronous and async
+// stream paths so FilterData behaves identically regardless of Config.Async.
+func (of *objectFilter) captureActive(relevantdDetections []objectdetection.Detection, now time.Time) (active bool, trackIDs []int) {
+	if of.tracker != nil {
+		return of.tracker.update(relevantdDetections, now)
+	}
+	return len(relevantdDetections) > 0, nil
 }
 
 // Constructor for the object filter camera
@@ -91,10 +246,92 @@ func newObjectFilter(ctx context.Context, deps resource.Dependencies, conf resou
 			return nil, err
 		}
 	}
-	of.visionService = of.visionServices[newConf.VisionServices[0]]
+	of.serviceConfigs = make(map[string]VisionServiceConfig)
+	for _, svcConf := range newConf.VisionServiceConfigs {
+		of.serviceConfigs[svcConf.Name] = svcConf
+	}
+	of.fusion = newConf.Fusion
+	if of.fusion == "" {
+		of.fusion = FusionUnion
+	}
+	of.fusionMinServices = newConf.FusionMinServices
+	if of.fusionMinServices == 0 {
+		of.fusionMinServices = len(newConf.VisionServices)
+	}
+	of.fusionIoUThreshold = newConf.FusionIoUThreshold
+	if of.fusionIoUThreshold == 0 {
+		of.fusionIoUThreshold = defaultFusionIoUThreshold
+	}
+	of.fusionThreshold = newConf.FusionThreshold
+
+	if newConf.Filter != "" {
+		of.filterExpr, err = compileFilter(newConf.Filter)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		of.filterExpr = labelsConfidenceFilter(newConf.Labels, newConf.Confidence)
+	}
+
+	if newConf.Tracking {
+		cooldown := time.Duration(newConf.Cooldown * float64(time.Second))
+		if cooldown == 0 {
+			cooldown = 2 * time.Second
+		}
+		of.tracker = newTracker(newConf.MinHits, newConf.HitsWindow, newConf.MaxAge, cooldown, newConf.IoUMatch)
+	}
+
+	if len(newConf.Regions) > 0 || len(newConf.BlurLabels) > 0 {
+		of.regions = newRegionSet(newConf.Regions, newConf.BlurLabels)
+	}
+
+	of.async = newConf.Async
+	of.maxInflight = newConf.MaxInflight
+	of.maxStale = time.Duration(newConf.MaxStaleMs) * time.Millisecond
+
+	of.events = newEventBus()
+
 	return of, nil
 }
 
+// detectAll runs every configured vision service against img concurrently and returns each
+// service's raw detections, keyed by service name. When DoCommand({"vision-service": ...}) has
+// restricted detection to a single service, only that service is queried.
+func (of *objectFilter) detectAll(ctx context.Context, img image.Image) ([]serviceDetections, error) {
+	active := of.currentActiveService()
+	visionServices := of.visionServices
+	if active != "" {
+		visionServices = map[string]vision.Service{active: of.visionServices[active]}
+	}
+	results := make([]serviceDetections, len(visionServices))
+
+	i := 0
+	names := make([]string, len(visionServices))
+	services := make([]vision.Service, len(visionServices))
+	for name, svc := range visionServices {
+		names[i] = name
+		services[i] = svc
+		i++
+	}
+
+	g, gCtx := errgroup.WithContext(ctx)
+	for idx := range names {
+		idx := idx
+		g.Go(func() error {
+			detections, err := services[idx].Detections(gCtx, img, nil)
+			if err != nil {
+				return fmt.Errorf("vision service %s: %w", names[idx], err)
+			}
+			results[idx] = serviceDetections{service: names[idx], detections: detections}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
 // Returns the unfiltered source camera images
 func (of *objectFilter) Images(ctx context.Context) ([]camera.NamedImage, resource.ResponseMetadata, error) {
 	images, meta, err := of.camera.Images(ctx)
@@ -129,6 +366,13 @@ func (of *objectFilter) Stream(ctx context.Context, errHandlers ...gostream.Erro
 	if err != nil {
 		return nil, err
 	}
+	if of.async {
+		pipeline := newAsyncPipeline(of, cameraStream, of.maxInflight, of.maxStale)
+		of.pipelineMu.Lock()
+		of.pipeline = pipeline
+		of.pipelineMu.Unlock()
+		return asyncFilterStream{pipeline, of}, nil
+	}
 	return filterStream{cameraStream, of}, nil
 }
 
@@ -144,22 +388,44 @@ func (fs filterStream) Next(ctx context.Context) (image.Image, func(), error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	// Provide image to vision service and get object detections
-	detections, err := fs.of.visionService.Detections(ctx, img, nil)
+	// Run every configured vision service against the frame and fuse their detections according
+	// to the active fusion policy.
+	byService, err := fs.of.detectAll(ctx, img)
 	if err != nil {
 		return nil, nil, err
 	}
-	// Filter the detected labels according to the filter configuration
+	fused := fs.of.fuse(byService)
+
+	// Apply the compiled filter expression (or its Labels/Confidence sugar) to select the
+	// detections relevant for overlay and data capture.
+	bounds := img.Bounds()
+	fctx := frameCtx{frameWidth: bounds.Dx(), frameHeight: bounds.Dy(), detections: fused}
 	var relevantdDetections []objectdetection.Detection
-	for _, detection := range detections {
-		if (slices.Contains(fs.of.conf.Labels, detection.Label())) && (detection.Score() >= fs.of.conf.Confidence) {
-			relevantdDetections = append(relevantdDetections, detection)
+	for _, d := range fused {
+		if fs.of.filterExpr(d, fctx) {
+			relevantdDetections = append(relevantdDetections, d)
 		}
 	}
-	// In the case of a data manager request, no relevant detections and data filtering true return no capture
-	if (ctx.Value(data.FromDMContextKey{}) == true) && (len(relevantdDetections) == 0) && fs.of.conf.FilterData {
+	// Apply include/exclude region-of-interest membership on top of the label-expression filter.
+	if fs.of.regions != nil {
+		relevantdDetections = fs.of.regions.filterDetections(relevantdDetections, image.Point{X: bounds.Dx(), Y: bounds.Dy()})
+	}
+	// Debounce the capture decision across frames with the temporal tracker, when enabled, so a
+	// single bad frame doesn't drop an ongoing capture and a single spurious frame doesn't trigger
+	// one. With tracking disabled this reduces to the original single-frame check.
+	captureActive, trackIDs := fs.of.captureActive(relevantdDetections, time.Now())
+	// Publish this frame's relevant detections to any live DoCommand subscriptions.
+	frameSeq := atomic.AddUint64(&fs.of.frameSeq, 1)
+	fs.of.events.publish(frameSeq, relevantdDetections, trackIDs)
+	// In the case of a data manager request, no active capture and data filtering true return no capture
+	if (ctx.Value(data.FromDMContextKey{}) == true) && !captureActive && fs.of.conf.FilterData {
 		return nil, release, data.ErrNoCaptureToStore
 	}
+	// Blur any "blur" mode regions and any detections whose label is configured for blurring,
+	// regardless of whether they passed the label-expression filter above.
+	if fs.of.regions != nil {
+		img = fs.of.regions.blur(img, fused)
+	}
 	// Overlay only the selected / configured detection labels and boxes onto the source image
 	if (len(relevantdDetections) > 0) && fs.of.conf.DisplayBoxes {
 		modImg, err := objectdetection.Overlay(img, relevantdDetections)
@@ -177,12 +443,74 @@ func (fs filterStream) Close(ctx context.Context) error {
 	return fs.cameraStream.Close(ctx)
 }
 
-// DoCommand allows changing the vision service to be used dynamically
+// DoCommand allows changing the vision service and fusion policy to be used dynamically
 func (of *objectFilter) DoCommand(ctx context.Context, cmd map[string]interface{}) (map[string]interface{}, error) {
-	val, ok := cmd["vision-service"].(string)
-	if ok {
-		of.visionService = of.visionServices[val]
+	if val, ok := cmd["vision-service"].(string); ok {
+		if val != "" {
+			if _, exists := of.visionServices[val]; !exists {
+				return nil, fmt.Errorf("vision service %q is not configured", val)
+			}
+		}
+		of.activeServiceMu.Lock()
+		of.activeService = val
+		of.activeServiceMu.Unlock()
+		if val == "" {
+			return map[string]interface{}{"result": "Vision service restriction cleared, querying all configured services"}, nil
+		}
 		return map[string]interface{}{"result": fmt.Sprintf("Vision service changed to: %s", val)}, nil
 	}
-	return nil, fmt.Errorf("vision service could not be changed to: %s", val)
+	if val, ok := cmd["fusion"].(string); ok {
+		if err := validateFusionMode(val); err != nil {
+			return nil, err
+		}
+		of.fusionMu.Lock()
+		of.fusion = val
+		of.fusionMu.Unlock()
+		return map[string]interface{}{"result": fmt.Sprintf("Fusion policy changed to: %s", val)}, nil
+	}
+	if val, ok := cmd["fusion"].(bool); ok && val {
+		return map[string]interface{}{"fusion": of.currentFusion()}, nil
+	}
+	if val, ok := cmd["stats"].(bool); ok && val {
+		of.pipelineMu.Lock()
+		pipeline := of.pipeline
+		of.pipelineMu.Unlock()
+		if pipeline == nil {
+			return nil, fmt.Errorf("stats are only available once an async stream has been opened")
+		}
+		snapshot := pipeline.stats.snapshot()
+		if stopped, stopErr, stoppedAt := pipeline.stoppedState(); stopped {
+			snapshot["puller_stopped"] = true
+			snapshot["puller_error"] = stopErr.Error()
+			snapshot["puller_stopped_at"] = stoppedAt
+		}
+		return snapshot, nil
+	}
+	if id, ok := cmd["subscribe"].(string); ok {
+		var labels []string
+		if rawLabels, ok := cmd["labels"].([]interface{}); ok {
+			for _, l := range rawLabels {
+				if label, ok := l.(string); ok {
+					labels = append(labels, label)
+				}
+			}
+		}
+		minConfidence, _ := cmd["min_confidence"].(float64)
+		of.events.subscribe(id, labels, minConfidence)
+		of.events.cleanupIdle()
+		return map[string]interface{}{"result": fmt.Sprintf("Subscribed: %s", id)}, nil
+	}
+	if id, ok := cmd["poll"].(string); ok {
+		since, _ := cmd["since"].(float64)
+		events, found := of.events.poll(id, uint64(since))
+		if !found {
+			return nil, fmt.Errorf("unknown subscription: %s", id)
+		}
+		return map[string]interface{}{"events": events}, nil
+	}
+	if id, ok := cmd["unsubscribe"].(string); ok {
+		of.events.unsubscribe(id)
+		return map[string]interface{}{"result": fmt.Sprintf("Unsubscribed: %s", id)}, nil
+	}
+	return nil, fmt.Errorf("unsupported command: %v", cmd)
 }
@@ -0,0 +1,281 @@
+// This file implements region-of-interest masks: named polygons, in normalized image
+// coordinates, that either restrict which detections are considered relevant (include/exclude)
+// or mark pixels to be blurred in the outgoing frame for privacy (blur). Detections whose label is
+// listed in blur_labels are blurred the same way, regardless of region membership.
+
+package mycamera
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"slices"
+	"sync"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Region modes.
+const (
+	RegionInclude = "include"
+	RegionExclude = "exclude"
+	RegionBlur    = "blur"
+)
+
+// blurRadius is the box-blur radius, in pixels, used to approximate a gaussian blur over masked
+// regions.
+const blurRadius = 12
+
+// Region is a named polygon, in normalized [0,1] image coordinates, with a mode describing how it
+// affects detections or the outgoing frame.
+type Region struct {
+	// Name identifies the region in validation errors.
+	Name string `json:"name"`
+	// Mode is one of "include", "exclude", or "blur".
+	Mode string `json:"mode"`
+	// Polygon lists the region's vertices as normalized (x, y) pairs in [0, 1].
+	Polygon [][2]float64 `json:"polygon"`
+}
+
+// validate checks that a region has a recognized mode and a non-degenerate polygon.
+func (r Region) validate() error {
+	switch r.Mode {
+	case RegionInclude, RegionExclude, RegionBlur:
+	default:
+		return fmt.Errorf("region %q: unknown mode %q", r.Name, r.Mode)
+	}
+	if len(r.Polygon) < 3 {
+		return fmt.Errorf("region %q: polygon needs at least 3 vertices", r.Name)
+	}
+	return nil
+}
+
+// rasterizedRegion is a Region's polygon scaled to the pixel dimensions of a specific frame size.
+type rasterizedRegion struct {
+	mode    string
+	polygon []image.Point
+}
+
+// regionSet rasterizes a camera's configured regions against each frame size it sees, and applies
+// include/exclude filtering and privacy blurring.
+type regionSet struct {
+	regions    []Region
+	blurLabels []string
+
+	mu    sync.Mutex
+	cache map[image.Point][]rasterizedRegion
+}
+
+// newRegionSet builds a regionSet from Config.Regions and Config.BlurLabels.
+func newRegionSet(regions []Region, blurLabels []string) *regionSet {
+	return &regionSet{regions: regions, blurLabels: blurLabels, cache: make(map[image.Point][]rasterizedRegion)}
+}
+
+// rasterizedFor returns the configured regions scaled to size, rasterizing and caching them on
+// first use for that size.
+func (rs *regionSet) rasterizedFor(size image.Point) []rasterizedRegion {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if cached, ok := rs.cache[size]; ok {
+		return cached
+	}
+	out := make([]rasterizedRegion, len(rs.regions))
+	for i, r := range rs.regions {
+		pts := make([]image.Point, len(r.Polygon))
+		for j, p := range r.Polygon {
+			pts[j] = image.Point{X: int(p[0] * float64(size.X)), Y: int(p[1] * float64(size.Y))}
+		}
+		out[i] = rasterizedRegion{mode: r.Mode, polygon: pts}
+	}
+	rs.cache[size] = out
+	return out
+}
+
+// filterDetections applies include/exclude region membership, based on each detection's bounding
+// box center, to detections. Include regions are unioned: a detection passes if it falls inside
+// any one of them (or if no include regions are configured at all). Exclude regions are
+// subtracted from that result: a detection inside any exclude region is dropped regardless of
+// include membership.
+func (rs *regionSet) filterDetections(detections []objectdetection.Detection, size image.Point) []objectdetection.Detection {
+	regions := rs.rasterizedFor(size)
+
+	var includeRegions []rasterizedRegion
+	for _, r := range regions {
+		if r.mode == RegionInclude {
+			includeRegions = append(includeRegions, r)
+		}
+	}
+
+	var out []objectdetection.Detection
+	for _, d := range detections {
+		center := boxCenter(d.BoundingBox())
+
+		included := len(includeRegions) == 0
+		for _, r := range includeRegions {
+			if pointInPolygon(center, r.polygon) {
+				included = true
+				break
+			}
+		}
+
+		excluded := false
+		for _, r := range regions {
+			if r.mode == RegionExclude && pointInPolygon(center, r.polygon) {
+				excluded = true
+				break
+			}
+		}
+
+		if included && !excluded {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// blur returns img with pixels inside any "blur" mode region, and pixels inside any detection
+// whose label is in blurLabels, box-blurred. img is returned unchanged if nothing needs blurring.
+func (rs *regionSet) blur(img image.Image, detections []objectdetection.Detection) image.Image {
+	bounds := img.Bounds()
+	size := image.Point{X: bounds.Dx(), Y: bounds.Dy()}
+
+	mask := rs.blurMask(size, detections)
+	if mask == nil {
+		return img
+	}
+	return boxBlurMasked(toNRGBA(img), mask, size, blurRadius)
+}
+
+// blurMask marks every pixel that falls inside a "blur" mode region or inside a detection whose
+// label is configured for blurring. Returns nil if nothing is masked.
+func (rs *regionSet) blurMask(size image.Point, detections []objectdetection.Detection) []bool {
+	var mask []bool
+	ensure := func() []bool {
+		if mask == nil {
+			mask = make([]bool, size.X*size.Y)
+		}
+		return mask
+	}
+
+	for _, r := range rs.rasterizedFor(size) {
+		if r.mode != RegionBlur {
+			continue
+		}
+		m := ensure()
+		minPt, maxPt := polygonBounds(r.polygon, size)
+		for y := minPt.Y; y < maxPt.Y; y++ {
+			for x := minPt.X; x < maxPt.X; x++ {
+				if pointInPolygon(image.Point{X: x, Y: y}, r.polygon) {
+					m[y*size.X+x] = true
+				}
+			}
+		}
+	}
+
+	for _, d := range detections {
+		if !slices.Contains(rs.blurLabels, d.Label()) {
+			continue
+		}
+		m := ensure()
+		box := d.BoundingBox()
+		minX, minY := clamp(box.Min.X, 0, size.X), clamp(box.Min.Y, 0, size.Y)
+		maxX, maxY := clamp(box.Max.X, 0, size.X), clamp(box.Max.Y, 0, size.Y)
+		for y := minY; y < maxY; y++ {
+			for x := minX; x < maxX; x++ {
+				m[y*size.X+x] = true
+			}
+		}
+	}
+	return mask
+}
+
+// pointInPolygon reports whether pt lies inside polygon, using the even-odd rule.
+func pointInPolygon(pt image.Point, polygon []image.Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		pi, pj := polygon[i], polygon[j]
+		if (pi.Y > pt.Y) != (pj.Y > pt.Y) {
+			xIntersect := float64(pj.X-pi.X)*float64(pt.Y-pi.Y)/float64(pj.Y-pi.Y) + float64(pi.X)
+			if float64(pt.X) < xIntersect {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+// polygonBounds returns the pixel bounding box of polygon, clamped to [0, size).
+func polygonBounds(polygon []image.Point, size image.Point) (image.Point, image.Point) {
+	minPt := image.Point{X: size.X, Y: size.Y}
+	maxPt := image.Point{}
+	for _, p := range polygon {
+		minPt.X, minPt.Y = min(minPt.X, p.X), min(minPt.Y, p.Y)
+		maxPt.X, maxPt.Y = max(maxPt.X, p.X), max(maxPt.Y, p.Y)
+	}
+	minPt.X, minPt.Y = clamp(minPt.X, 0, size.X), clamp(minPt.Y, 0, size.Y)
+	maxPt.X, maxPt.Y = clamp(maxPt.X, 0, size.X), clamp(maxPt.Y, 0, size.Y)
+	return minPt, maxPt
+}
+
+func boxCenter(box *image.Rectangle) image.Point {
+	return image.Point{X: (box.Min.X + box.Max.X) / 2, Y: (box.Min.Y + box.Max.Y) / 2}
+}
+
+func clamp(v, lo, hi int) int {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
+
+// toNRGBA converts img to *image.NRGBA, so pixels can be read and written directly.
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba
+	}
+	out := image.NewNRGBA(img.Bounds())
+	draw.Draw(out, out.Bounds(), img, img.Bounds().Min, draw.Src)
+	return out
+}
+
+// boxBlurMasked returns a copy of img with every pixel marked in mask replaced by the average of
+// its radius neighborhood, approximating a gaussian blur.
+func boxBlurMasked(img *image.NRGBA, mask []bool, size image.Point, radius int) *image.NRGBA {
+	out := image.NewNRGBA(img.Bounds())
+	copy(out.Pix, img.Pix)
+
+	for y := 0; y < size.Y; y++ {
+		for x := 0; x < size.X; x++ {
+			if !mask[y*size.X+x] {
+				continue
+			}
+			var rSum, gSum, bSum, aSum, count int
+			for dy := -radius; dy <= radius; dy++ {
+				ny := y + dy
+				if ny < 0 || ny >= size.Y {
+					continue
+				}
+				for dx := -radius; dx <= radius; dx++ {
+					nx := x + dx
+					if nx < 0 || nx >= size.X {
+						continue
+					}
+					r, g, b, a := img.At(img.Bounds().Min.X+nx, img.Bounds().Min.Y+ny).RGBA()
+					rSum += int(r >> 8)
+					gSum += int(g >> 8)
+					bSum += int(b >> 8)
+					aSum += int(a >> 8)
+					count++
+				}
+			}
+			out.SetNRGBA(img.Bounds().Min.X+x, img.Bounds().Min.Y+y, color.NRGBA{
+				R: uint8(rSum / count), G: uint8(gSum / count), B: uint8(bSum / count), A: uint8(aSum / count),
+			})
+		}
+	}
+	return out
+}
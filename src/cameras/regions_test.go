@@ -0,0 +1,69 @@
+package mycamera
+
+import (
+	"image"
+	"testing"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+func TestRegionSetFilterDetectionsUnionsIncludeRegions(t *testing.T) {
+	rs := newRegionSet([]Region{
+		{Name: "left", Mode: RegionInclude, Polygon: [][2]float64{{0, 0}, {0.5, 0}, {0.5, 1}, {0, 1}}},
+		{Name: "right", Mode: RegionInclude, Polygon: [][2]float64{{0.5, 0}, {1, 0}, {1, 1}, {0.5, 1}}},
+	}, nil)
+	size := image.Point{X: 100, Y: 100}
+
+	// One detection centered in each include region; neither is inside both, so ANDing them would
+	// drop both. Unioned, each should pass because it's inside at least one.
+	inLeft := detectionAt("person", image.Rect(10, 40, 30, 60))
+	inRight := detectionAt("person", image.Rect(70, 40, 90, 60))
+
+	out := rs.filterDetections([]objectdetection.Detection{inLeft, inRight}, size)
+	if len(out) != 2 {
+		t.Fatalf("expected both detections to pass with unioned include regions, got %d", len(out))
+	}
+}
+
+func TestRegionSetFilterDetectionsDropsOutsideAllIncludeRegions(t *testing.T) {
+	rs := newRegionSet([]Region{
+		{Name: "left", Mode: RegionInclude, Polygon: [][2]float64{{0, 0}, {0.5, 0}, {0.5, 1}, {0, 1}}},
+	}, nil)
+	size := image.Point{X: 100, Y: 100}
+
+	outside := detectionAt("person", image.Rect(70, 40, 90, 60))
+	out := rs.filterDetections([]objectdetection.Detection{outside}, size)
+	if len(out) != 0 {
+		t.Fatalf("expected detection outside the only include region to be dropped, got %d", len(out))
+	}
+}
+
+func TestRegionSetFilterDetectionsExcludesAnyMatch(t *testing.T) {
+	rs := newRegionSet([]Region{
+		{Name: "door", Mode: RegionExclude, Polygon: [][2]float64{{0.4, 0.4}, {0.6, 0.4}, {0.6, 0.6}, {0.4, 0.6}}},
+	}, nil)
+	size := image.Point{X: 100, Y: 100}
+
+	inExclude := detectionAt("person", image.Rect(45, 45, 55, 55))
+	elsewhere := detectionAt("person", image.Rect(5, 5, 15, 15))
+
+	out := rs.filterDetections([]objectdetection.Detection{inExclude, elsewhere}, size)
+	if len(out) != 1 {
+		t.Fatalf("expected only the detection outside the exclude region to pass, got %d", len(out))
+	}
+	box := out[0].BoundingBox()
+	if box.Min.X != 5 {
+		t.Fatalf("expected the surviving detection to be the one outside the exclude region, got box %v", box)
+	}
+}
+
+func TestRegionSetFilterDetectionsNoRegionsPassesEverything(t *testing.T) {
+	rs := newRegionSet(nil, nil)
+	size := image.Point{X: 100, Y: 100}
+	d := detectionAt("person", image.Rect(5, 5, 15, 15))
+
+	out := rs.filterDetections([]objectdetection.Detection{d}, size)
+	if len(out) != 1 {
+		t.Fatalf("expected detection to pass through unfiltered with no regions configured, got %d", len(out))
+	}
+}
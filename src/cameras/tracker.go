@@ -0,0 +1,150 @@
+// This file implements a small temporal tracker used to debounce FilterData decisions across
+// frames. A single bad frame no longer drops an otherwise ongoing capture, and a single spurious
+// detection no longer triggers one: a track must be seen in min_hits of the last hits_window
+// frames before it is "confirmed", and a confirmed track keeps capture active for cooldown after
+// its last sighting.
+
+package mycamera
+
+import (
+	"image"
+	"sync"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// Defaults applied when the corresponding Config field is left at its zero value.
+const (
+	defaultMinHits    = 3
+	defaultHitsWindow = 5
+	defaultMaxAge     = 10
+	defaultIoUMatch   = 0.3
+)
+
+// track is a single object being followed across frames.
+type track struct {
+	id       int
+	label    string
+	box      *image.Rectangle
+	history  []bool
+	age      int
+	lastSeen time.Time
+}
+
+// pushHistory records whether the track was matched this frame, keeping at most window entries.
+func (t *track) pushHistory(hit bool, window int) {
+	t.history = append(t.history, hit)
+	if len(t.history) > window {
+		t.history = t.history[len(t.history)-window:]
+	}
+}
+
+// hits returns how many of the retained history entries were matches.
+func (t *track) hits() int {
+	n := 0
+	for _, hit := range t.history {
+		if hit {
+			n++
+		}
+	}
+	return n
+}
+
+// tracker maintains the set of live tracks and decides, frame by frame, whether a confirmed track
+// is currently active.
+type tracker struct {
+	mu sync.Mutex
+
+	tracks []*track
+
+	minHits    int
+	hitsWindow int
+	maxAge     int
+	cooldown   time.Duration
+	iouMatch   float64
+
+	nextID int
+
+	// lastConfirmed is when a confirmed track was last seen; zero until the first confirmation.
+	lastConfirmed time.Time
+}
+
+// newTracker builds a tracker from its Config-derived parameters.
+func newTracker(minHits, hitsWindow, maxAge int, cooldown time.Duration, iouMatch float64) *tracker {
+	if minHits == 0 {
+		minHits = defaultMinHits
+	}
+	if hitsWindow == 0 {
+		hitsWindow = defaultHitsWindow
+	}
+	if maxAge == 0 {
+		maxAge = defaultMaxAge
+	}
+	if iouMatch == 0 {
+		iouMatch = defaultIoUMatch
+	}
+	return &tracker{minHits: minHits, hitsWindow: hitsWindow, maxAge: maxAge, cooldown: cooldown, iouMatch: iouMatch}
+}
+
+// update associates detections with existing tracks (by label and IoU), starts new tracks for
+// unmatched detections, drops tracks older than maxAge, and reports whether capture is active, i.e.
+// a confirmed track was seen within cooldown of now. The cooldown keeps running from that sighting
+// even once the track drops below minHits or ages out. trackIDs, aligned with detections,
+// carries each detection's (possibly newly assigned) track id.
+func (tr *tracker) update(detections []objectdetection.Detection, now time.Time) (active bool, trackIDs []int) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	trackIDs = make([]int, len(detections))
+	matched := make([]bool, len(detections))
+	for _, t := range tr.tracks {
+		bestIdx, bestIoU := -1, tr.iouMatch
+		for i, d := range detections {
+			if matched[i] || d.Label() != t.label {
+				continue
+			}
+			if v := iou(t.box, d.BoundingBox()); v >= bestIoU {
+				bestIoU, bestIdx = v, i
+			}
+		}
+		if bestIdx >= 0 {
+			matched[bestIdx] = true
+			trackIDs[bestIdx] = t.id
+			t.box = detections[bestIdx].BoundingBox()
+			t.age = 0
+			t.lastSeen = now
+			t.pushHistory(true, tr.hitsWindow)
+		} else {
+			t.age++
+			t.pushHistory(false, tr.hitsWindow)
+		}
+	}
+
+	for i, d := range detections {
+		if matched[i] {
+			continue
+		}
+		tr.nextID++
+		t := &track{id: tr.nextID, label: d.Label(), box: d.BoundingBox(), lastSeen: now}
+		t.pushHistory(true, tr.hitsWindow)
+		tr.tracks = append(tr.tracks, t)
+		trackIDs[i] = t.id
+	}
+
+	live := tr.tracks[:0]
+	for _, t := range tr.tracks {
+		if t.age <= tr.maxAge {
+			live = append(live, t)
+		}
+	}
+	tr.tracks = live
+
+	for _, t := range tr.tracks {
+		if t.lastSeen.Equal(now) && t.hits() >= tr.minHits {
+			tr.lastConfirmed = now
+		}
+	}
+	active = !tr.lastConfirmed.IsZero() && now.Sub(tr.lastConfirmed) <= tr.cooldown
+	return active, trackIDs
+}
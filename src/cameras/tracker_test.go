@@ -0,0 +1,96 @@
+package mycamera
+
+import (
+	"image"
+	"testing"
+	"time"
+
+	"go.viam.com/rdk/vision/objectdetection"
+)
+
+// detectionAt builds a synthetic detection for label at the given pixel box, for use in tracker
+// and region test sequences.
+func detectionAt(label string, box image.Rectangle) objectdetection.Detection {
+	return objectdetection.NewDetection(&box, 1.0, label)
+}
+
+func TestTrackerConfirmsAfterMinHits(t *testing.T) {
+	tr := newTracker(3, 5, 10, time.Second, 0.3)
+	box := image.Rect(10, 10, 50, 50)
+	now := time.Unix(0, 0)
+
+	var active bool
+	for i := 0; i < 3; i++ {
+		active, _ = tr.update([]objectdetection.Detection{detectionAt("person", box)}, now)
+		now = now.Add(100 * time.Millisecond)
+	}
+	if !active {
+		t.Fatalf("expected track to be confirmed active after min_hits sightings")
+	}
+}
+
+func TestTrackerNotActiveBeforeMinHits(t *testing.T) {
+	tr := newTracker(3, 5, 10, time.Second, 0.3)
+	box := image.Rect(10, 10, 50, 50)
+	now := time.Unix(0, 0)
+
+	active, _ := tr.update([]objectdetection.Detection{detectionAt("person", box)}, now)
+	if active {
+		t.Fatalf("expected track to not yet be confirmed on the first sighting")
+	}
+}
+
+func TestTrackerSurvivesSingleMissedFrame(t *testing.T) {
+	tr := newTracker(3, 5, 10, time.Second, 0.3)
+	box := image.Rect(10, 10, 50, 50)
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 3; i++ {
+		tr.update([]objectdetection.Detection{detectionAt("person", box)}, now)
+		now = now.Add(100 * time.Millisecond)
+	}
+
+	// A single missed frame shouldn't drop the confirmed track, since it's within cooldown.
+	active, trackIDs := tr.update(nil, now)
+	if !active {
+		t.Fatalf("expected track to survive a single missed frame within cooldown")
+	}
+	if len(trackIDs) != 0 {
+		t.Fatalf("expected no track ids for a frame with no detections, got %v", trackIDs)
+	}
+}
+
+func TestTrackerInactiveOnceCooldownElapses(t *testing.T) {
+	tr := newTracker(3, 5, 10, time.Second, 0.3)
+	box := image.Rect(10, 10, 50, 50)
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 3; i++ {
+		tr.update([]objectdetection.Detection{detectionAt("person", box)}, now)
+		now = now.Add(100 * time.Millisecond)
+	}
+
+	// Once the cooldown has elapsed with no further sightings, the track should no longer count as
+	// active, even though it hasn't aged out of the tracks slice yet.
+	now = now.Add(2 * time.Second)
+	active, _ := tr.update(nil, now)
+	if active {
+		t.Fatalf("expected track to stop being active once cooldown has elapsed")
+	}
+}
+
+func TestTrackerAssignsStableTrackID(t *testing.T) {
+	tr := newTracker(1, 5, 10, time.Second, 0.3)
+	box := image.Rect(10, 10, 50, 50)
+	now := time.Unix(0, 0)
+
+	_, firstIDs := tr.update([]objectdetection.Detection{detectionAt("person", box)}, now)
+	now = now.Add(100 * time.Millisecond)
+	// Slightly shifted box, should still match the same track via IoU.
+	shifted := image.Rect(12, 12, 52, 52)
+	_, secondIDs := tr.update([]objectdetection.Detection{detectionAt("person", shifted)}, now)
+
+	if len(firstIDs) != 1 || len(secondIDs) != 1 || firstIDs[0] != secondIDs[0] {
+		t.Fatalf("expected the same track id across matched frames, got %v then %v", firstIDs, secondIDs)
+	}
+}